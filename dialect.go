@@ -0,0 +1,213 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// csvDelimiterCandidates are the delimiters sniffDialect scores when
+// auto-detecting a CSV dialect.
+var csvDelimiterCandidates = []rune{',', ';', '\t', '|', ':'}
+
+// CSVDialect describes the structural conventions of a CSV-like file, as
+// detected by sniffDialect or overridden by CLI flags.
+type CSVDialect struct {
+	Delimiter  rune
+	Quote      rune
+	HasHeader  bool
+	LineEnding string
+}
+
+// sniffDialect samples the first ~64 KiB of a file and infers its CSV
+// dialect: the field delimiter, quote character, whether the first row is
+// a header, and the line ending style.
+func sniffDialect(sample string) CSVDialect {
+	lineEnding := "\n"
+	if strings.Contains(sample, "\r\n") {
+		lineEnding = "\r\n"
+	}
+
+	lines := strings.Split(sample, lineEnding)
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > 64 {
+		lines = lines[:64]
+	}
+
+	delimiter, fieldCount := pickDelimiter(lines)
+
+	return CSVDialect{
+		Delimiter:  delimiter,
+		Quote:      detectQuote(lines, delimiter),
+		HasHeader:  detectHeader(lines, delimiter, fieldCount),
+		LineEnding: lineEnding,
+	}
+}
+
+// pickDelimiter scores each candidate delimiter by how consistent the
+// resulting field count is across lines: the mode of the per-line field
+// counts, its frequency, and its variance. The candidate with the highest
+// mode frequency and lowest variance wins, ties broken by field count.
+func pickDelimiter(lines []string) (delimiter rune, fieldCount int) {
+	delimiter = ','
+	fieldCount = 1
+	bestFreq := -1.0
+	bestVariance := 0.0
+
+	for _, candidate := range csvDelimiterCandidates {
+		counts := make([]int, 0, len(lines))
+		for _, line := range lines {
+			if line == "" {
+				continue
+			}
+			counts = append(counts, strings.Count(line, string(candidate))+1)
+		}
+		if len(counts) == 0 {
+			continue
+		}
+
+		mode, freq := modeFrequency(counts)
+		if mode < 2 {
+			continue // never splits a line: not a real delimiter
+		}
+		variance := varianceOf(counts)
+
+		better := freq > bestFreq ||
+			(freq == bestFreq && variance < bestVariance) ||
+			(freq == bestFreq && variance == bestVariance && mode > fieldCount)
+		if better {
+			delimiter = candidate
+			fieldCount = mode
+			bestFreq = freq
+			bestVariance = variance
+		}
+	}
+
+	return delimiter, fieldCount
+}
+
+// modeFrequency returns the most common value in counts and the fraction
+// of counts it accounts for.
+func modeFrequency(counts []int) (mode int, freq float64) {
+	tally := make(map[int]int, len(counts))
+	for _, c := range counts {
+		tally[c]++
+	}
+
+	best := 0
+	for value, n := range tally {
+		if n > best {
+			best = n
+			mode = value
+		}
+	}
+	return mode, float64(best) / float64(len(counts))
+}
+
+func varianceOf(counts []int) float64 {
+	if len(counts) == 0 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, c := range counts {
+		mean += float64(c)
+	}
+	mean /= float64(len(counts))
+
+	variance := 0.0
+	for _, c := range counts {
+		d := float64(c) - mean
+		variance += d * d
+	}
+	return variance / float64(len(counts))
+}
+
+// detectQuote picks ' over the default " only when there's structural
+// evidence it's actually being used to quote fields — a candidate that
+// wraps a whole field, immediately after one delimiter and immediately
+// before the next. Raw character frequency isn't enough: ordinary prose
+// like "it's" or "don't" racks up apostrophes without ever quoting
+// anything, and misdetecting those as the quote char would feed
+// quoteSwapReader (reader.go) a dialect that breaks every such row.
+func detectQuote(lines []string, delimiter rune) rune {
+	if quotedFieldCount(lines, delimiter, '\'') > 0 {
+		return '\''
+	}
+	return '"'
+}
+
+// quotedFieldCount counts fields that open with quote immediately at a
+// field boundary (the start of a line or right after delimiter) and close
+// with quote immediately before the next field boundary (delimiter or end
+// of line) — i.e. quote is actually wrapping the field, as opposed to
+// appearing incidentally inside it. This has to scan rune-by-rune rather
+// than split on delimiter first, since a genuinely quoted field is
+// allowed to contain the delimiter itself (that's the whole point of
+// quoting it).
+func quotedFieldCount(lines []string, delimiter rune, quote rune) int {
+	count := 0
+	for _, line := range lines {
+		runes := []rune(line)
+		n := len(runes)
+		atBoundary := true
+
+		for i := 0; i < n; i++ {
+			switch {
+			case runes[i] == delimiter:
+				atBoundary = true
+				continue
+			case atBoundary && runes[i] == quote:
+				j := i + 1
+				for j < n && runes[j] != quote {
+					j++
+				}
+				if j < n && (j+1 == n || runes[j+1] == delimiter) {
+					count++
+					i = j
+				}
+			}
+			atBoundary = false
+		}
+	}
+	return count
+}
+
+// detectHeader guesses whether the first line is a header by comparing
+// how "numeric-looking" it is against the following data lines: headers
+// are expected to have no numeric cells while data rows mostly do.
+func detectHeader(lines []string, delimiter rune, fieldCount int) bool {
+	if len(lines) < 2 {
+		return true
+	}
+
+	if numericCellCount(lines[0], delimiter) > 0 {
+		return false
+	}
+
+	dataNumeric := 0
+	dataLines := 0
+	for _, line := range lines[1:] {
+		if line == "" {
+			continue
+		}
+		dataNumeric += numericCellCount(line, delimiter)
+		dataLines++
+		if dataLines >= 5 {
+			break
+		}
+	}
+
+	return dataNumeric > 0
+}
+
+func numericCellCount(line string, delimiter rune) int {
+	count := 0
+	for _, cell := range strings.Split(line, string(delimiter)) {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(cell), 64); err == nil {
+			count++
+		}
+	}
+	return count
+}
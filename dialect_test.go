@@ -0,0 +1,147 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPickDelimiterComma(t *testing.T) {
+	lines := []string{"a,b,c", "1,2,3", "4,5,6"}
+	delimiter, fieldCount := pickDelimiter(lines)
+	if delimiter != ',' || fieldCount != 3 {
+		t.Errorf("pickDelimiter = %q, %d; want ',', 3", delimiter, fieldCount)
+	}
+}
+
+func TestPickDelimiterSemicolon(t *testing.T) {
+	lines := []string{"a;b;c;d", "1;2;3;4", "5;6;7;8"}
+	delimiter, fieldCount := pickDelimiter(lines)
+	if delimiter != ';' || fieldCount != 4 {
+		t.Errorf("pickDelimiter = %q, %d; want ';', 4", delimiter, fieldCount)
+	}
+}
+
+func TestPickDelimiterTab(t *testing.T) {
+	lines := []string{"a\tb", "1\t2", "3\t4"}
+	delimiter, _ := pickDelimiter(lines)
+	if delimiter != '\t' {
+		t.Errorf("pickDelimiter = %q, want '\\t'", delimiter)
+	}
+}
+
+func TestDetectHeaderTrue(t *testing.T) {
+	lines := []string{"name,age", "alice,30", "bob,25"}
+	if !detectHeader(lines, ',', 2) {
+		t.Error("detectHeader = false, want true for a non-numeric first row")
+	}
+}
+
+func TestDetectHeaderFalse(t *testing.T) {
+	lines := []string{"1,30", "2,25", "3,40"}
+	if detectHeader(lines, ',', 2) {
+		t.Error("detectHeader = true, want false when every row is numeric")
+	}
+}
+
+func TestDetectQuoteFieldsWrappedInApostrophes(t *testing.T) {
+	lines := []string{"name;greeting", "alice;'hello world'", "bob;'hi there'"}
+	if q := detectQuote(lines, ';'); q != '\'' {
+		t.Errorf("detectQuote = %q, want '\\''", q)
+	}
+}
+
+func TestDetectQuoteDefaultsToDoubleQuote(t *testing.T) {
+	lines := []string{`name;greeting`, `alice;"hello world"`, `bob;"hi there"`}
+	if q := detectQuote(lines, ';'); q != '"' {
+		t.Errorf("detectQuote = %q, want '\"'", q)
+	}
+}
+
+// TestDetectQuoteIgnoresContractions covers the bug where an ordinary
+// comma-delimited file with prose containing apostrophes ("it's",
+// "don't") was misdetected as '-quoted purely on character frequency,
+// even though none of those apostrophes sit at a field boundary.
+func TestDetectQuoteIgnoresContractions(t *testing.T) {
+	lines := []string{
+		"name,note",
+		"alice,it's sunny today",
+		"bob,don't worry",
+		"carol,can't wait",
+	}
+	if q := detectQuote(lines, ','); q != '"' {
+		t.Errorf("detectQuote = %q, want '\"' (apostrophes here are contractions, not quoting)", q)
+	}
+}
+
+func TestSniffDialect(t *testing.T) {
+	sample := "name;age\r\nalice;30\r\nbob;25\r\n"
+	dialect := sniffDialect(sample)
+
+	if dialect.Delimiter != ';' {
+		t.Errorf("Delimiter = %q, want ';'", dialect.Delimiter)
+	}
+	if dialect.LineEnding != "\r\n" {
+		t.Errorf("LineEnding = %q, want \"\\r\\n\"", dialect.LineEnding)
+	}
+	if !dialect.HasHeader {
+		t.Error("HasHeader = false, want true")
+	}
+}
+
+// TestCsvReaderHandlesNonDoubleQuoteDialect covers the bug where a
+// '-quoted field containing the delimiter (e.g. 'hello; world' in a
+// ;-delimited file) got mis-split by encoding/csv, which only ever
+// recognizes " as a quote character.
+func TestCsvReaderHandlesNonDoubleQuoteDialect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quoted.csv")
+	content := "alice;'hello; world'\nbob;hi\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := newCsvReader(path, ';', command_args{delimiter: ';', quote: NO_DELIMITER_SET_VALUE, noHeader: true})
+	if err != nil {
+		t.Fatalf("newCsvReader: %v", err)
+	}
+	defer r.Close()
+
+	row, err := r.NextRow()
+	if err != nil {
+		t.Fatalf("NextRow: %v", err)
+	}
+	if len(row) != 2 {
+		t.Fatalf("row = %v, want 2 fields", row)
+	}
+	if row[1] != "hello; world" {
+		t.Errorf("row[1] = %q, want %q", row[1], "hello; world")
+	}
+}
+
+// TestCsvReaderHandlesPlainCSVWithContractions is the end-to-end repro
+// for the contraction-misdetection bug: a plain comma-delimited file
+// whose only apostrophes are contractions in prose must read normally,
+// not have every data row rejected by encoding/csv as a bad quote.
+func TestCsvReaderHandlesPlainCSVWithContractions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.csv")
+	content := "alice,it's sunny today\nbob,don't worry\ncarol,can't wait\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := newCsvReader(path, NO_DELIMITER_SET_VALUE, command_args{quote: NO_DELIMITER_SET_VALUE, noHeader: true})
+	if err != nil {
+		t.Fatalf("newCsvReader: %v", err)
+	}
+	defer r.Close()
+
+	for _, want := range []string{"it's sunny today", "don't worry", "can't wait"} {
+		row, err := r.NextRow()
+		if err != nil {
+			t.Fatalf("NextRow: %v", err)
+		}
+		if row[1] != want {
+			t.Errorf("row[1] = %q, want %q", row[1], want)
+		}
+	}
+}
@@ -1,20 +1,18 @@
 package main
 
 import (
-	"encoding/csv"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
-	"strings"
 	"unicode/utf8"
 
 	"tablespy/table"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/xuri/excelize/v2"
 )
 
 var NO_DELIMITER_SET_VALUE rune = 0
@@ -28,10 +26,18 @@ type model struct {
 }
 
 type command_args struct {
-	filename     string
-	file_type    string
-	delimiter    rune
-	table_height int
+	filename        string
+	file_type       string
+	delimiter       rune
+	quote           rune
+	noHeader        bool
+	table_height    int
+	query           string
+	sheet           string
+	preview         bool
+	previewPosition table.Position
+	export          string
+	maxColWidth     int
 }
 
 func (m model) Init() tea.Cmd { return nil }
@@ -60,141 +66,69 @@ func (m model) View() string {
 func main() {
 	args := parseArgs()
 
-	columns, rows := readFile(args)
-
-	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-	)
-
-	m := model{t}
-	if _, err := tea.NewProgram(m).Run(); err != nil {
-		fmt.Println("Error running program:", err)
-		os.Exit(1)
-	}
-
-	//	print_records(records)
-}
-
-func readFile(args command_args) ([]string, [][]string) {
-	content, err := os.ReadFile(args.filename)
-
+	reader, err := newReader(args)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading file '%s': %v\n", args.filename, err)
+		fmt.Fprintln(os.Stderr, "Error opening file:", err)
 		os.Exit(1)
 	}
+	defer reader.Close()
 
-	contentString := string(content)
-
-	switch args.file_type {
-	case "csv":
-		return parseCsv(contentString, args)
-	case "excel":
-		return parseXlsx(contentString)
-	default:
-		if strings.HasSuffix(args.filename, ".xlsx") || strings.HasSuffix(args.filename, ".xls") {
-			return parseXlsx(contentString)
-		} else {
-			return parseCsv(contentString, args)
+	if args.export != "" {
+		if err := exportToStdout(reader, args.export); err != nil {
+			fmt.Fprintln(os.Stderr, "Error exporting:", err)
+			os.Exit(1)
 		}
+		return
 	}
-}
 
-func parseCsv(content string, args command_args) ([]string, [][]string) {
-	var delimiter = args.delimiter
-	if args.delimiter == NO_DELIMITER_SET_VALUE {
-		delimiter = guessDelimiter(content)
+	t := table.New(
+		table.WithColumns(reader.Columns()),
+		table.WithOnDemandRows(&readerRowSource{reader: reader}),
+		table.WithPreview(args.preview, args.previewPosition),
+		table.WithMaxColWidth(args.maxColWidth),
+	)
+	if args.query != "" {
+		t.SetFilter(args.query)
 	}
 
-	reader := csv.NewReader(strings.NewReader(content))
-	reader.Comma = delimiter
-	records, err := reader.ReadAll()
-
-	if err != nil {
-		fmt.Println("Error reading CSV from string:", err)
+	m := model{t}
+	if _, err := tea.NewProgram(m).Run(); err != nil {
+		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
-
-	records = trim(records)
-
-	columns, rows := records[0], records[1:]
-	return columns, rows
 }
 
-func guessDelimiter(content string) rune {
-	semi_count := 0
-	comma_count := 0
-	for _, char := range content {
-		if char == ';' {
-			semi_count += 1
-		} else if char == ',' {
-			comma_count += 1
+// exportToStdout reads every row out of reader and writes them to stdout
+// in format, skipping the TUI entirely, for use in scripting pipelines.
+func exportToStdout(reader Reader, format string) error {
+	var rows [][]string
+	for {
+		row, err := reader.NextRow()
+		if err == io.EOF {
+			break
 		}
-	}
-
-	if semi_count > comma_count {
-		return ';'
-	} else {
-		return ','
-	}
-}
-
-func parseXlsx(content string) ([]string, [][]string) {
-	f, err := excelize.OpenReader(strings.NewReader(content))
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-	defer func() {
-		if err := f.Close(); err != nil {
-			fmt.Println(err)
-		}
-	}()
-
-	sheet := f.GetSheetList()[0]
-
-	rows, err := f.GetRows(sheet)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-
-	records := trim(rows)
-
-	columns, rows := records[0], records[1:]
-	return columns, rows
-}
-
-// func print_records(records [][]string) {
-// 	paddings := calc_paddings(records)
-
-// 	for i := 0; i < len(records); i++ {
-// 		record := records[i]
-
-// 		for j := 0; j < len(record); j++ {
-// 			padding := paddings[j]
-// 			fmt.Printf("%-*s", padding, record[j])
-// 		}
-// 		fmt.Println()
-// 	}
-// }
-
-func trim(records [][]string) [][]string {
-	for i := 0; i < len(records); i++ {
-		record := records[i]
-		for j := 0; j < len(record); j++ {
-			record[j] = strings.Trim(record[j], " ")
+		if err != nil {
+			return err
 		}
+		rows = append(rows, row)
 	}
-	return records
+	return table.WriteRows(os.Stdout, format, reader.Columns(), rows)
 }
 
 func parseArgs() command_args {
 	flag.Usage = printUsage
 
-	fileTypePtr := flag.String("file_type", "auto", "force specific filetype, values: 'excel' or 'csv'")
+	fileTypePtr := flag.String("file_type", "auto", "force specific filetype, values: 'csv', 'tsv', 'excel', 'jsonl' or 'parquet'")
 	delimiterPtr := flag.String("delimiter", "auto", "char delimiter for when parsing csv, like ',' or ';'")
+	quotePtr := flag.String("quote", "auto", "quote char for when parsing csv, like '\"' or '\\''")
+	noHeaderPtr := flag.Bool("no-header", false, "treat the first row of a csv file as data, not a header")
 	heightPtr := flag.Int("height", 20, "max height for the table in rows")
+	queryPtr := flag.String("query", "", "initial fuzzy filter query to apply on startup")
+	sheetPtr := flag.String("sheet", "", "sheet name to read, for excel files (defaults to the first sheet)")
+	previewPtr := flag.Bool("preview", false, "show the cell preview pane on startup")
+	previewPositionPtr := flag.String("preview-position", "bottom", "where to draw the preview pane, values: 'right', 'bottom' or 'hidden'")
+	exportPtr := flag.String("export", "", "skip the TUI and write every row to stdout in this format, values: 'csv', 'md', 'json' or 'xlsx'")
+	maxColWidthPtr := flag.Int("max-col-width", 40, "cap the auto-computed width of any column at this many characters")
 
 	flag.Parse()
 
@@ -207,28 +141,76 @@ func parseArgs() command_args {
 
 	filename := args[0]
 
-	allowedFileTypes := []string{"csv", "excel", "auto"}
+	allowedFileTypes := []string{"csv", "tsv", "excel", "jsonl", "parquet", "auto"}
 	if !slices.Contains(allowedFileTypes, *fileTypePtr) {
 		fmt.Fprintln(os.Stderr, "file_type can only be the following types: ", allowedFileTypes)
 		os.Exit(0)
 	}
 
-	var delimiter rune
-	if *delimiterPtr == "auto" {
-		delimiter = NO_DELIMITER_SET_VALUE
-	} else if len(*delimiterPtr) != 1 {
-		fmt.Fprintln(os.Stderr, "delimiter can only be a single char")
+	delimiter, err := parseSingleCharFlag("delimiter", *delimiterPtr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(0)
+	}
+
+	quote, err := parseSingleCharFlag("quote", *quotePtr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(0)
+	}
+
+	previewPosition, err := parsePreviewPosition(*previewPositionPtr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(0)
+	}
+
+	allowedExportFormats := []string{"", "csv", "md", "json", "xlsx"}
+	if !slices.Contains(allowedExportFormats, *exportPtr) {
+		fmt.Fprintln(os.Stderr, "export can only be the following formats: ", allowedExportFormats[1:])
 		os.Exit(0)
-	} else {
-		delimiter, _ = utf8.DecodeRuneInString(*delimiterPtr)
 	}
 
 	return command_args{
-		filename:     filename,
-		file_type:    *fileTypePtr,
-		table_height: *heightPtr,
-		delimiter:    delimiter,
+		filename:        filename,
+		file_type:       *fileTypePtr,
+		table_height:    *heightPtr,
+		delimiter:       delimiter,
+		quote:           quote,
+		noHeader:        *noHeaderPtr,
+		query:           *queryPtr,
+		sheet:           *sheetPtr,
+		preview:         *previewPtr,
+		previewPosition: previewPosition,
+		export:          *exportPtr,
+		maxColWidth:     *maxColWidthPtr,
+	}
+}
+
+func parsePreviewPosition(value string) (table.Position, error) {
+	switch value {
+	case "right":
+		return table.PositionRight, nil
+	case "bottom":
+		return table.PositionBottom, nil
+	case "hidden":
+		return table.PositionHidden, nil
+	default:
+		return table.PositionHidden, fmt.Errorf("preview-position can only be 'right', 'bottom' or 'hidden', got %q", value)
+	}
+}
+
+// parseSingleCharFlag turns a flag value of "auto" or a single character
+// into a rune, using NO_DELIMITER_SET_VALUE to mean "auto-detect".
+func parseSingleCharFlag(name, value string) (rune, error) {
+	if value == "auto" {
+		return NO_DELIMITER_SET_VALUE, nil
+	}
+	if len(value) != 1 {
+		return 0, fmt.Errorf("%s can only be a single char", name)
 	}
+	r, _ := utf8.DecodeRuneInString(value)
+	return r, nil
 }
 
 func printUsage() {
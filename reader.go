@@ -0,0 +1,421 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/xuri/excelize/v2"
+)
+
+// Reader streams rows from a tabular data source one row at a time, so the
+// table model never has to hold more of a file in memory than the rows it
+// has actually scrolled past.
+type Reader interface {
+	// Columns returns the header row.
+	Columns() []string
+	// NextRow returns the next data row. It returns io.EOF once the
+	// source is exhausted.
+	NextRow() ([]string, error)
+	Close() error
+}
+
+// newReader opens args.filename and picks a Reader implementation based on
+// args.file_type, falling back to sniffing the extension when file_type is
+// "auto".
+func newReader(args command_args) (Reader, error) {
+	fileType := args.file_type
+	if fileType == "auto" {
+		fileType = detectFileType(args.filename)
+	}
+
+	switch fileType {
+	case "excel":
+		return newXlsxReader(args.filename, args.sheet)
+	case "tsv":
+		return newCsvReader(args.filename, '\t', args)
+	case "jsonl":
+		return newJSONLReader(args.filename)
+	case "parquet":
+		return newParquetReader(args.filename)
+	default:
+		return newCsvReader(args.filename, args.delimiter, args)
+	}
+}
+
+func detectFileType(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xlsx", ".xls":
+		return "excel"
+	case ".tsv":
+		return "tsv"
+	case ".jsonl", ".ndjson":
+		return "jsonl"
+	case ".parquet":
+		return "parquet"
+	default:
+		return "csv"
+	}
+}
+
+// readerRowSource adapts a Reader to table.RowSource.
+type readerRowSource struct {
+	reader Reader
+	err    error
+}
+
+func (s *readerRowSource) Next() ([]string, bool) {
+	row, err := s.reader.NextRow()
+	if err != nil {
+		if err != io.EOF {
+			s.err = err
+		}
+		return nil, false
+	}
+	return row, true
+}
+
+func (s *readerRowSource) Err() error { return s.err }
+
+func trimRow(row []string) []string {
+	for i, value := range row {
+		row[i] = strings.Trim(value, " ")
+	}
+	return row
+}
+
+// csvReader streams rows from a CSV/TSV/etc file via encoding/csv, rather
+// than reading the whole file into memory up front.
+type csvReader struct {
+	file    *os.File
+	reader  *csv.Reader
+	columns []string
+	pending []string
+	dialect CSVDialect
+}
+
+func newCsvReader(filename string, delimiterOverride rune, args command_args) (*csvReader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", filename, err)
+	}
+
+	sample, err := readSample(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	dialect := sniffDialect(sample)
+	if delimiterOverride != NO_DELIMITER_SET_VALUE {
+		dialect.Delimiter = delimiterOverride
+	}
+	if args.quote != NO_DELIMITER_SET_VALUE {
+		dialect.Quote = args.quote
+	}
+	if args.noHeader {
+		dialect.HasHeader = false
+	}
+
+	var src io.Reader = bufio.NewReader(f)
+	if dialect.Quote != '"' {
+		// encoding/csv only ever treats '"' as a quote character, so swap
+		// the detected quote rune for '"' in the byte stream (and back,
+		// symmetrically, for any literal '"' already in the file) before
+		// handing it to csv.Reader. That lets fields like 'hello; world'
+		// get tokenized correctly instead of mis-splitting on the
+		// delimiter inside them.
+		src = &quoteSwapReader{r: src, from: byte(dialect.Quote), to: '"'}
+	}
+
+	reader := csv.NewReader(src)
+	reader.Comma = dialect.Delimiter
+
+	r := &csvReader{file: f, reader: reader, dialect: dialect}
+
+	if dialect.HasHeader {
+		columns, err := reader.Read()
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("reading header from %s: %w", filename, err)
+		}
+		r.columns = r.finishRow(columns)
+		return r, nil
+	}
+
+	firstRow, err := reader.Read()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	r.pending = r.finishRow(firstRow)
+	r.columns = make([]string, len(r.pending))
+	for i := range r.columns {
+		r.columns[i] = fmt.Sprintf("Column %d", i+1)
+	}
+	return r, nil
+}
+
+func (r *csvReader) Columns() []string { return r.columns }
+
+func (r *csvReader) NextRow() ([]string, error) {
+	if r.pending != nil {
+		row := r.pending
+		r.pending = nil
+		return row, nil
+	}
+
+	row, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	return r.finishRow(row), nil
+}
+
+func (r *csvReader) Close() error { return r.file.Close() }
+
+// finishRow trims surrounding spaces. Quote handling happens upstream, in the
+// quoteSwapReader wrapped around the file when the dialect's quote char
+// isn't '"', so by the time a row reaches here csv.Reader has already
+// stripped it correctly.
+func (r *csvReader) finishRow(row []string) []string {
+	return trimRow(row)
+}
+
+// quoteSwapReader swaps a single-byte quote rune for '"' (and back) as it
+// streams, so encoding/csv — which only ever recognizes '"' as a quote
+// character — can correctly tokenize a file quoted with something else,
+// like the single quotes in 'hello; world'.
+type quoteSwapReader struct {
+	r        io.Reader
+	from, to byte
+}
+
+func (s *quoteSwapReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	for i := 0; i < n; i++ {
+		switch p[i] {
+		case s.from:
+			p[i] = s.to
+		case s.to:
+			p[i] = s.from
+		}
+	}
+	return n, err
+}
+
+// readSample reads up to 64 KiB from the start of f for dialect sniffing,
+// leaving the file positioned back at the start for the real read.
+func readSample(f *os.File) (string, error) {
+	buf := make([]byte, 64*1024)
+	n, err := f.Read(buf)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return string(buf[:n]), nil
+}
+
+// xlsxReader streams rows from a sheet using excelize's Rows() iterator
+// instead of GetRows, so a multi-GB workbook isn't loaded in full.
+type xlsxReader struct {
+	file    *excelize.File
+	rows    *excelize.Rows
+	columns []string
+}
+
+func newXlsxReader(filename, sheet string) (*xlsxReader, error) {
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", filename, err)
+	}
+
+	if sheet == "" {
+		sheet = f.GetSheetList()[0]
+	}
+
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("reading sheet %q: %w", sheet, err)
+	}
+
+	r := &xlsxReader{file: f, rows: rows}
+
+	if !rows.Next() {
+		r.Close()
+		return nil, fmt.Errorf("sheet %q in %s has no header row", sheet, filename)
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	r.columns = trimRow(columns)
+
+	return r, nil
+}
+
+func (r *xlsxReader) Columns() []string { return r.columns }
+
+func (r *xlsxReader) NextRow() ([]string, error) {
+	if !r.rows.Next() {
+		if err := r.rows.Error(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	row, err := r.rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	return trimRow(row), nil
+}
+
+func (r *xlsxReader) Close() error {
+	if err := r.rows.Close(); err != nil {
+		return err
+	}
+	return r.file.Close()
+}
+
+// jsonlReader streams one JSON object per line. Columns are the union of
+// keys seen on the first line, sorted for determinism since JSON object
+// key order isn't preserved through decoding.
+type jsonlReader struct {
+	file    *os.File
+	scanner *bufio.Scanner
+	columns []string
+	pending map[string]string
+}
+
+func newJSONLReader(filename string) (*jsonlReader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", filename, err)
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	if !scanner.Scan() {
+		f.Close()
+		return nil, fmt.Errorf("reading %s: file has no rows", filename)
+	}
+
+	first, err := decodeJSONLine(scanner.Bytes())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	columns := make([]string, 0, len(first))
+	for col := range first {
+		columns = append(columns, col)
+	}
+	slices.Sort(columns)
+
+	return &jsonlReader{file: f, scanner: scanner, columns: columns, pending: first}, nil
+}
+
+func (r *jsonlReader) Columns() []string { return r.columns }
+
+func (r *jsonlReader) NextRow() ([]string, error) {
+	record := r.pending
+	if record == nil {
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		var err error
+		record, err = decodeJSONLine(r.scanner.Bytes())
+		if err != nil {
+			return nil, err
+		}
+	}
+	r.pending = nil
+
+	row := make([]string, len(r.columns))
+	for i, col := range r.columns {
+		row[i] = record[col]
+	}
+	return row, nil
+}
+
+func (r *jsonlReader) Close() error { return r.file.Close() }
+
+func decodeJSONLine(line []byte) (map[string]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return nil, fmt.Errorf("parsing json line: %w", err)
+	}
+
+	record := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if s, ok := value.(string); ok {
+			record[key] = s
+			continue
+		}
+		b, _ := json.Marshal(value)
+		record[key] = string(b)
+	}
+	return record, nil
+}
+
+// parquetReader streams rows out of a Parquet file's row groups via
+// parquet-go, converting each column value to its string representation.
+type parquetReader struct {
+	file    *os.File
+	reader  *parquet.Reader
+	columns []string
+}
+
+func newParquetReader(filename string) (*parquetReader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", filename, err)
+	}
+
+	reader := parquet.NewReader(f)
+
+	fields := reader.Schema().Fields()
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = field.Name()
+	}
+
+	return &parquetReader{file: f, reader: reader, columns: columns}, nil
+}
+
+func (r *parquetReader) Columns() []string { return r.columns }
+
+func (r *parquetReader) NextRow() ([]string, error) {
+	record := make(map[string]any, len(r.columns))
+	if err := r.reader.Read(&record); err != nil {
+		return nil, err
+	}
+
+	row := make([]string, len(r.columns))
+	for i, col := range r.columns {
+		row[i] = fmt.Sprint(record[col])
+	}
+	return row, nil
+}
+
+func (r *parquetReader) Close() error {
+	if err := r.reader.Close(); err != nil {
+		return err
+	}
+	return r.file.Close()
+}
@@ -0,0 +1,89 @@
+package table
+
+const (
+	defaultMaxColWidth = 40
+	minColWidth        = 3
+)
+
+// WithMaxColWidth caps the auto-computed width of any column at n
+// characters, so one pathological cell can't push every other column
+// off-screen. Defaults to 40.
+func WithMaxColWidth(n int) Option {
+	return func(m *Model) {
+		m.maxColWidth = n
+	}
+}
+
+// colWidthAt returns the display width for column i: an explicit resize
+// from ColGrow/ColShrink if one was set, otherwise the auto-computed
+// width capped at maxColWidth.
+func (m *Model) colWidthAt(i int) int {
+	if i < len(m.colWidthOverride) && m.colWidthOverride[i] != 0 {
+		return m.colWidthOverride[i]
+	}
+
+	width := m.paddings[i]
+	if m.maxColWidth > 0 && width > m.maxColWidth {
+		width = m.maxColWidth
+	}
+	return width
+}
+
+// resizeCol grows or shrinks column col's width by delta, overriding its
+// auto-computed width from then on.
+func (m *Model) resizeCol(col, delta int) {
+	if col < 0 || col >= len(m.colWidthOverride) {
+		return
+	}
+
+	width := m.colWidthAt(col) + delta
+	if width < minColWidth {
+		width = minColWidth
+	}
+	m.colWidthOverride[col] = width
+}
+
+func (m *Model) isHidden(i int) bool {
+	return i >= 0 && i < len(m.hidden) && m.hidden[i]
+}
+
+// nextVisibleCol steps from `from` in direction dir (+1/-1), skipping
+// hidden columns, and stops at the first visible column or at the edge
+// of the table if none is found.
+func (m *Model) nextVisibleCol(from, dir int) int {
+	col := from
+	for {
+		next := col + dir
+		if next < 0 || next >= len(m.cols) {
+			return col
+		}
+		col = next
+		if !m.isHidden(col) {
+			return col
+		}
+	}
+}
+
+// visibleColumns returns the column indices to render, in order: pinned
+// columns first (so they stay anchored at the left edge), then the
+// remaining unhidden columns starting at cursor_col.
+func (m *Model) visibleColumns() []int {
+	cols := make([]int, 0, len(m.cols))
+	pinned := make(map[int]bool)
+
+	for i := 0; i < len(m.cols); i++ {
+		if i < len(m.pinned) && m.pinned[i] && !m.isHidden(i) {
+			cols = append(cols, i)
+			pinned[i] = true
+		}
+	}
+
+	for i := m.cursor_col; i < len(m.cols); i++ {
+		if m.isHidden(i) || pinned[i] {
+			continue
+		}
+		cols = append(cols, i)
+	}
+
+	return cols
+}
@@ -0,0 +1,67 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestColWidthAtUsesOverrideThenCap(t *testing.T) {
+	m := New(
+		WithColumns([]string{"a", "b"}),
+		WithRows([][]string{{"x", "y"}}),
+		WithMaxColWidth(5),
+	)
+	m.paddings[0] = 20
+
+	if w := m.colWidthAt(0); w != 5 {
+		t.Errorf("colWidthAt(0) = %d, want auto width capped to 5", w)
+	}
+
+	m.resizeCol(0, 10)
+	if w := m.colWidthAt(0); w != 15 {
+		t.Errorf("colWidthAt(0) after resize = %d, want 15 (override bypasses the cap)", w)
+	}
+}
+
+func TestResizeColRespectsMinWidth(t *testing.T) {
+	m := New(WithColumns([]string{"a"}), WithRows([][]string{{"x"}}))
+
+	m.resizeCol(0, -100)
+	if w := m.colWidthAt(0); w != minColWidth {
+		t.Errorf("colWidthAt(0) = %d, want floor of %d", w, minColWidth)
+	}
+}
+
+func TestNextVisibleColSkipsHidden(t *testing.T) {
+	m := New(WithColumns([]string{"a", "b", "c", "d"}), WithRows([][]string{{"1", "2", "3", "4"}}))
+	m.hidden[1] = true
+	m.hidden[2] = true
+
+	if got := m.nextVisibleCol(0, 1); got != 3 {
+		t.Errorf("nextVisibleCol(0, 1) = %d, want 3 (skipping hidden 1 and 2)", got)
+	}
+}
+
+func TestNextVisibleColStopsAtEdge(t *testing.T) {
+	// With every column to the right hidden, nextVisibleCol has nowhere
+	// visible to land and stops at the last column it reached.
+	m := New(WithColumns([]string{"a", "b"}), WithRows([][]string{{"1", "2"}}))
+	m.hidden[1] = true
+
+	if got := m.nextVisibleCol(0, 1); got != 1 {
+		t.Errorf("nextVisibleCol(0, 1) = %d, want 1 (stops at the last column tried)", got)
+	}
+}
+
+func TestVisibleColumnsPinnedFirstThenUnhidden(t *testing.T) {
+	m := New(WithColumns([]string{"a", "b", "c", "d"}), WithRows([][]string{{"1", "2", "3", "4"}}))
+	m.pinned[2] = true
+	m.hidden[1] = true
+	m.cursor_col = 0
+
+	got := m.visibleColumns()
+	want := []int{2, 0, 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("visibleColumns = %v, want %v", got, want)
+	}
+}
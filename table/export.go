@@ -0,0 +1,180 @@
+package table
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ExportRows writes rows to filename, choosing a format from its file
+// extension: .csv, .md, .json or .xlsx.
+func ExportRows(filename string, cols []string, rows [][]string) error {
+	format, err := formatFromExtension(filename)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", filename, err)
+	}
+	defer f.Close()
+
+	return WriteRows(f, format, cols, rows)
+}
+
+func formatFromExtension(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return "csv", nil
+	case ".md":
+		return "md", nil
+	case ".json":
+		return "json", nil
+	case ".xlsx":
+		return "xlsx", nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q, use .csv, .md, .json or .xlsx", filepath.Ext(filename))
+	}
+}
+
+// WriteRows writes rows to w in the given format ("csv", "md", "json" or
+// "xlsx"). It backs both ExportRows (file exports) and the CLI's --export
+// stdout dump.
+func WriteRows(w io.Writer, format string, cols []string, rows [][]string) error {
+	switch format {
+	case "csv":
+		return writeCSV(w, cols, rows)
+	case "md":
+		return writeMarkdown(w, cols, rows)
+	case "json":
+		return writeJSON(w, cols, rows)
+	case "xlsx":
+		return writeXlsx(w, cols, rows)
+	default:
+		return fmt.Errorf("unsupported export format %q, use csv, md, json or xlsx", format)
+	}
+}
+
+func writeCSV(w io.Writer, cols []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(cols); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeMarkdown renders rows as a GitHub-flavored Markdown table, with
+// column widths computed from the widest cell and '|' escaped in values.
+func writeMarkdown(w io.Writer, cols []string, rows [][]string) error {
+	widths := make([]int, len(cols))
+	for i, col := range cols {
+		widths[i] = len(escapeMarkdownCell(col))
+	}
+	for _, row := range rows {
+		for i, value := range row {
+			if i >= len(widths) {
+				continue
+			}
+			if l := len(escapeMarkdownCell(value)); l > widths[i] {
+				widths[i] = l
+			}
+		}
+	}
+
+	if err := writeMarkdownRow(w, cols, widths); err != nil {
+		return err
+	}
+
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("-", max(width, 3))
+	}
+	if err := writeMarkdownRow(w, separators, widths); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := writeMarkdownRow(w, row, widths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMarkdownRow(w io.Writer, cells []string, widths []int) error {
+	var b strings.Builder
+	b.WriteString("|")
+	for i, width := range widths {
+		value := ""
+		if i < len(cells) {
+			value = escapeMarkdownCell(cells[i])
+		}
+		fmt.Fprintf(&b, " %-*s |", width, value)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeMarkdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+func writeJSON(w io.Writer, cols []string, rows [][]string) error {
+	records := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		record := make(map[string]string, len(cols))
+		for j, col := range cols {
+			if j < len(row) {
+				record[col] = row[j]
+			}
+		}
+		records[i] = record
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+func writeXlsx(w io.Writer, cols []string, rows [][]string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := f.GetSheetName(0)
+
+	for i, col := range cols {
+		cell, err := excelize.CoordinatesToCellName(i+1, 1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellValue(sheet, cell, col); err != nil {
+			return err
+		}
+	}
+
+	for r, row := range rows {
+		for c, value := range row {
+			cell, err := excelize.CoordinatesToCellName(c+1, r+2)
+			if err != nil {
+				return err
+			}
+			if err := f.SetCellValue(sheet, cell, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return f.Write(w)
+}
@@ -0,0 +1,127 @@
+package table
+
+import "strings"
+
+// fuzzyScore computes a subsequence match score of query against target,
+// similar in spirit to fzf's matching algorithm. It returns the score and
+// the rune positions in target that were matched, in ascending order. A
+// negative score (and a nil slice) means query does not match at all.
+func fuzzyScore(query, target string) (int, []int) {
+	if query == "" {
+		return 0, nil
+	}
+
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(target))
+
+	positions := make([]int, 0, len(q))
+	score := 0
+	qi := 0
+	prevMatch := -2
+
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+
+		positions = append(positions, ti)
+		score += 1
+
+		if prevMatch == ti-1 {
+			score += 5 // bonus for consecutive matches
+		}
+		if ti == 0 || isWordBoundary(t[ti-1]) {
+			score += 10 // bonus for word-boundary hits
+		}
+
+		prevMatch = ti
+		qi++
+	}
+
+	if qi < len(q) {
+		return -1, nil
+	}
+
+	return score, positions
+}
+
+func isWordBoundary(r rune) bool {
+	return r == ' ' || r == '_' || r == '-' || r == '.' || r == '/' || r == ','
+}
+
+// rowMatch holds the result of matching a query against a row: the summed
+// score of the row's matching cells and the matched positions per cell, keyed
+// by column index.
+type rowMatch struct {
+	score     int
+	positions map[int][]int
+}
+
+// matchRow scores a row against query, matching each cell independently and
+// summing the scores of cells that match. A row matches if at least one
+// cell matches.
+func matchRow(query string, row []string) (rowMatch, bool) {
+	if query == "" {
+		return rowMatch{}, true
+	}
+
+	result := rowMatch{positions: make(map[int][]int)}
+	matched := false
+
+	for col, cell := range row {
+		score, positions := fuzzyScore(query, cell)
+		if score < 0 {
+			continue
+		}
+		matched = true
+		result.score += score
+		result.positions[col] = positions
+	}
+
+	return result, matched
+}
+
+// SetFilter applies a fuzzy filter query to the table, recomputing the set
+// of visible rows and resetting the cursor onto the first match.
+func (m *Model) SetFilter(query string) {
+	m.filterQuery = query
+	m.applyFilter()
+}
+
+// ClearFilter removes any active filter and restores all rows.
+func (m *Model) ClearFilter() {
+	m.SetFilter("")
+}
+
+func (m *Model) applyFilter() {
+	if m.filterQuery != "" {
+		// Filtering needs to see the whole dataset, not just what's been
+		// scrolled past so far.
+		m.drainSource()
+	}
+
+	// A visual selection holds positions into the filteredIndex we're
+	// about to rebuild (sort.go rebuilds rows the same way); once that
+	// order changes, the old positions point at the wrong rows, so drop
+	// the selection rather than let Export silently grab the wrong ones.
+	m.selecting = false
+	m.selection = nil
+
+	m.filteredIndex = m.filteredIndex[:0]
+	m.cellMatches = make(map[int]map[int][]int)
+
+	for i, row := range m.rows {
+		match, ok := matchRow(m.filterQuery, row)
+		if !ok {
+			continue
+		}
+		m.filteredIndex = append(m.filteredIndex, i)
+		if len(match.positions) > 0 {
+			m.cellMatches[i] = match.positions
+		}
+	}
+
+	m.cursor_row = 0
+	m.viewport.SetYOffset(0)
+	m.UpdateViewport()
+}
@@ -0,0 +1,82 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScoreNoMatch(t *testing.T) {
+	score, positions := fuzzyScore("xyz", "hello world")
+	if score >= 0 || positions != nil {
+		t.Fatalf("fuzzyScore(%q, %q) = %d, %v; want negative score, nil positions", "xyz", "hello world", score, positions)
+	}
+}
+
+func TestFuzzyScoreEmptyQueryMatchesEverything(t *testing.T) {
+	score, positions := fuzzyScore("", "anything")
+	if score != 0 || positions != nil {
+		t.Fatalf("fuzzyScore(\"\", ...) = %d, %v; want 0, nil", score, positions)
+	}
+}
+
+func TestFuzzyScorePositions(t *testing.T) {
+	score, positions := fuzzyScore("hw", "hello world")
+	if score < 0 {
+		t.Fatalf("fuzzyScore(%q, %q) did not match", "hw", "hello world")
+	}
+	want := []int{0, 6}
+	if !reflect.DeepEqual(positions, want) {
+		t.Errorf("positions = %v, want %v", positions, want)
+	}
+}
+
+func TestFuzzyScoreRewardsConsecutiveMatches(t *testing.T) {
+	// "ab" lands consecutively in "zabz" (positions 1,2) but is split by a
+	// non-boundary character in "zazbz" (positions 1,3); neither match
+	// starts at position 0, so this isolates the consecutive-match bonus
+	// from the word-boundary one.
+	consecutive, _ := fuzzyScore("ab", "zabz")
+	scattered, _ := fuzzyScore("ab", "zazbz")
+	if consecutive <= scattered {
+		t.Errorf("expected consecutive match to outscore scattered match: got %d <= %d", consecutive, scattered)
+	}
+}
+
+func TestMatchRow(t *testing.T) {
+	row := []string{"alice", "engineering", "nyc"}
+
+	if _, ok := matchRow("", row); !ok {
+		t.Error("empty query should match any row")
+	}
+
+	match, ok := matchRow("eng", row)
+	if !ok {
+		t.Fatal("expected \"eng\" to match row")
+	}
+	if _, ok := match.positions[1]; !ok {
+		t.Errorf("expected match positions for column 1, got %v", match.positions)
+	}
+
+	if _, ok := matchRow("zzz", row); ok {
+		t.Error("expected \"zzz\" not to match row")
+	}
+}
+
+// TestSetFilterClearsStaleSelection covers the bug where a visual
+// selection (positions into the pre-filter filteredIndex) survived a
+// SetFilter call, so a subsequent yank/export would grab whatever rows
+// ended up at those positions in the new filtered view instead of the
+// ones the user actually selected.
+func TestSetFilterClearsStaleSelection(t *testing.T) {
+	m := New(
+		WithColumns([]string{"name"}),
+		WithRows([][]string{{"alice"}, {"bob"}, {"carol"}}),
+	)
+	m.selection = &[2]int{0, 1}
+
+	m.SetFilter("a")
+
+	if m.selection != nil {
+		t.Error("expected selection to be cleared after SetFilter changes the visible rows")
+	}
+}
@@ -0,0 +1,126 @@
+package table
+
+import (
+	"encoding/json"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Position selects where the preview pane is drawn relative to the table,
+// or whether it's shown at all.
+type Position int
+
+const (
+	PositionHidden Position = iota
+	PositionRight
+	PositionBottom
+)
+
+const (
+	previewWidth  = 50
+	previewHeight = 10
+)
+
+// WithPreview sets whether the cell preview pane starts visible and where
+// it's drawn. The pane can still be toggled at runtime with the Preview
+// keybinding ('p' by default).
+func WithPreview(enabled bool, pos Position) Option {
+	return func(m *Model) {
+		m.previewEnabled = enabled
+		m.previewPosition = pos
+	}
+}
+
+func (m Model) previewVisible() bool {
+	return m.previewEnabled && m.previewPosition != PositionHidden
+}
+
+// renderPreview lays the preview pane alongside or below content, the
+// current table rendering, if the preview is enabled.
+func (m *Model) renderPreview(content string) string {
+	if !m.previewVisible() {
+		return content
+	}
+
+	pane := m.previewPane()
+	if m.previewPosition == PositionRight {
+		return lipgloss.JoinHorizontal(lipgloss.Top, content, pane)
+	}
+	return content + "\n" + pane
+}
+
+func (m *Model) previewPane() string {
+	m.previewViewport.Width = previewWidth
+	m.previewViewport.Height = previewHeight
+	m.previewViewport.SetContent(formatPreviewContent(m.selectedCell()))
+
+	border := lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 1)
+
+	if m.previewPosition == PositionRight {
+		border = border.BorderLeft(true)
+	} else {
+		border = border.BorderTop(true)
+	}
+
+	return border.Render(m.previewViewport.View())
+}
+
+// selectedCell returns the value under the cursor, or "" if there is
+// nothing to show (e.g. an empty filtered view).
+func (m *Model) selectedCell() string {
+	if len(m.filteredIndex) == 0 || m.cursor_row >= len(m.filteredIndex) {
+		return ""
+	}
+
+	row := m.rows[m.filteredIndex[m.cursor_row]]
+	if m.cursor_col >= len(row) {
+		return ""
+	}
+	return row[m.cursor_col]
+}
+
+// formatPreviewContent pretty-prints value as JSON if it parses as an
+// object or array, and otherwise wraps it to the preview pane's width
+// as-is. Scalars (numbers, strings, bools) are deliberately left alone —
+// an ordinary numeric cell like "19.50" isn't "JSON" just because it
+// happens to parse as one, and round-tripping it through float64 would
+// reformat it (or lose precision on a large integer ID).
+func formatPreviewContent(value string) string {
+	if parsed := tryParseJSONContainer(value); parsed != nil {
+		if pretty, err := json.MarshalIndent(parsed, "", "  "); err == nil {
+			value = string(pretty)
+		}
+	}
+
+	return lipgloss.NewStyle().Width(previewWidth - 2).Render(value)
+}
+
+// tryParseJSONContainer parses value as JSON and returns it, but only if
+// it decodes to an object or array; otherwise (invalid JSON, a JSON scalar
+// or null) it returns nil.
+func tryParseJSONContainer(value string) any {
+	var parsed any
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		return nil
+	}
+	switch parsed.(type) {
+	case map[string]any, []any:
+		return parsed
+	default:
+		return nil
+	}
+}
+
+// resetPreviewScroll scrolls the preview pane back to the top, called
+// whenever the cursor moves onto a different cell.
+func (m *Model) resetPreviewScroll() {
+	m.previewViewport.GotoTop()
+}
+
+func newPreviewViewport() viewport.Model {
+	return viewport.New(previewWidth, previewHeight)
+}
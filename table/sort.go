@@ -0,0 +1,192 @@
+package table
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// colKind is the detected type of a column's values, used to pick a
+// comparator when sorting.
+type colKind int
+
+const (
+	kindString colKind = iota
+	kindNumber
+	kindDate
+)
+
+// dateLayouts are tried in order for each value when detecting whether a
+// column holds dates and when parsing it; every value is parsed with the
+// first layout that accepts it, so one column may mix layouts.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"01/02/2006",
+	"2006/01/02",
+	"02-01-2006",
+	"Jan 2, 2006",
+}
+
+// sortState caches the detected kind and parsed values for a column so that
+// repeat sorts on the same column are O(n log n) without re-parsing.
+type sortState struct {
+	col    int
+	kind   colKind
+	desc   bool
+	values []sortValue
+}
+
+// sortValue holds a row's parsed value for the sorted column, alongside
+// its original row index so the rows slice can be reordered directly.
+type sortValue struct {
+	row    int
+	number float64
+	date   time.Time
+	text   string
+}
+
+// Sort orders the table's rows by column col, ascending unless desc is
+// true. Repeat calls for the same column reuse the cached parse of that
+// column's values; the direction is always the one passed in (toggling on
+// repeat is done by SortCurrentColumn).
+func (m *Model) Sort(col int, desc bool) {
+	if col < 0 || col >= len(m.cols) {
+		return
+	}
+
+	// Sorting needs every row present, same as a non-empty filter.
+	m.drainSource()
+
+	if m.sortState == nil || m.sortState.col != col {
+		kind := detectColKind(m.rows, col)
+		m.sortState = &sortState{
+			col:    col,
+			kind:   kind,
+			values: parseColumn(m.rows, col, kind),
+		}
+	}
+	m.sortState.desc = desc
+
+	values := m.sortState.values
+	kind := m.sortState.kind
+
+	sort.SliceStable(values, func(i, j int) bool {
+		if desc {
+			return lessValue(values[j], values[i], kind)
+		}
+		return lessValue(values[i], values[j], kind)
+	})
+
+	sortedRows := make([][]string, len(m.rows))
+	for newPos, v := range values {
+		sortedRows[newPos] = m.rows[v.row]
+		values[newPos].row = newPos
+	}
+	m.rows = sortedRows
+
+	m.applyFilter()
+}
+
+// SortCurrentColumn sorts by the column under the cursor, toggling
+// direction if it's already the sorted column.
+func (m *Model) SortCurrentColumn() {
+	desc := false
+	if m.sortState != nil && m.sortState.col == m.cursor_col {
+		desc = !m.sortState.desc
+	}
+	m.Sort(m.cursor_col, desc)
+}
+
+func lessValue(a, b sortValue, kind colKind) bool {
+	switch kind {
+	case kindNumber:
+		return a.number < b.number
+	case kindDate:
+		return a.date.Before(b.date)
+	default:
+		return a.text < b.text
+	}
+}
+
+// detectColKind samples every value in column col and classifies it as
+// numeric if at least 90% parse as floats, else as a date if every
+// non-empty value parses under one of dateLayouts, else as a string.
+func detectColKind(rows [][]string, col int) colKind {
+	total := 0
+	numeric := 0
+	for _, row := range rows {
+		if col >= len(row) || row[col] == "" {
+			continue
+		}
+		total++
+		if _, err := strconv.ParseFloat(row[col], 64); err == nil {
+			numeric++
+		}
+	}
+	if total > 0 && float64(numeric)/float64(total) >= 0.9 {
+		return kindNumber
+	}
+
+	if total > 0 && allParseAsDate(rows, col) {
+		return kindDate
+	}
+
+	return kindString
+}
+
+func allParseAsDate(rows [][]string, col int) bool {
+	for _, row := range rows {
+		if col >= len(row) || row[col] == "" {
+			continue
+		}
+		if _, ok := parseDate(row[col]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func parseDate(value string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseColumn(rows [][]string, col int, kind colKind) []sortValue {
+	values := make([]sortValue, len(rows))
+	for i, row := range rows {
+		v := sortValue{row: i}
+		if col >= len(row) {
+			values[i] = v
+			continue
+		}
+
+		switch kind {
+		case kindNumber:
+			v.number, _ = strconv.ParseFloat(row[col], 64)
+		case kindDate:
+			v.date, _ = parseDate(row[col])
+		default:
+			v.text = strings.ToLower(row[col])
+		}
+		values[i] = v
+	}
+	return values
+}
+
+// sortIndicator returns the ▲/▼ glyph for col if it's the currently sorted
+// column, or "" otherwise.
+func (m *Model) sortIndicator(col int) string {
+	if m.sortState == nil || m.sortState.col != col {
+		return ""
+	}
+	if m.sortState.desc {
+		return " ▼"
+	}
+	return " ▲"
+}
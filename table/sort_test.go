@@ -0,0 +1,119 @@
+package table
+
+import "testing"
+
+func TestDetectColKindNumber(t *testing.T) {
+	rows := [][]string{{"1"}, {"2"}, {"3.5"}, {"4"}}
+	if kind := detectColKind(rows, 0); kind != kindNumber {
+		t.Errorf("detectColKind = %v, want kindNumber", kind)
+	}
+}
+
+func TestDetectColKindNumberToleratesAFewOutliers(t *testing.T) {
+	// 9/10 numeric clears the 90% threshold even with one bad value.
+	rows := make([][]string, 10)
+	for i := range rows {
+		rows[i] = []string{"1"}
+	}
+	rows[0] = []string{"not a number"}
+	if kind := detectColKind(rows, 0); kind != kindNumber {
+		t.Errorf("detectColKind = %v, want kindNumber", kind)
+	}
+}
+
+func TestDetectColKindDate(t *testing.T) {
+	rows := [][]string{{"2024-01-02"}, {"2024-03-04"}, {""}}
+	if kind := detectColKind(rows, 0); kind != kindDate {
+		t.Errorf("detectColKind = %v, want kindDate", kind)
+	}
+}
+
+func TestDetectColKindString(t *testing.T) {
+	rows := [][]string{{"alice"}, {"bob"}, {"carol"}}
+	if kind := detectColKind(rows, 0); kind != kindString {
+		t.Errorf("detectColKind = %v, want kindString", kind)
+	}
+}
+
+func TestDetectColKindEmptyColumn(t *testing.T) {
+	rows := [][]string{{""}, {""}}
+	if kind := detectColKind(rows, 0); kind != kindString {
+		t.Errorf("detectColKind = %v, want kindString for an all-empty column", kind)
+	}
+}
+
+func TestLessValueNumber(t *testing.T) {
+	a := sortValue{number: 1}
+	b := sortValue{number: 2}
+	if !lessValue(a, b, kindNumber) || lessValue(b, a, kindNumber) {
+		t.Error("lessValue did not order numbers correctly")
+	}
+}
+
+func TestLessValueString(t *testing.T) {
+	a := sortValue{text: "apple"}
+	b := sortValue{text: "banana"}
+	if !lessValue(a, b, kindString) || lessValue(b, a, kindString) {
+		t.Error("lessValue did not order strings correctly")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		ok    bool
+	}{
+		{"2024-01-02", true},
+		{"01/02/2024", true},
+		{"not a date", false},
+	}
+	for _, tt := range tests {
+		_, ok := parseDate(tt.value)
+		if ok != tt.ok {
+			t.Errorf("parseDate(%q) ok = %v, want %v", tt.value, ok, tt.ok)
+		}
+	}
+}
+
+func TestSortNumericColumnAscendingAndDescending(t *testing.T) {
+	m := New(
+		WithColumns([]string{"n"}),
+		WithRows([][]string{{"3"}, {"1"}, {"2"}}),
+	)
+
+	m.Sort(0, false)
+	if got := collectColumn(m, 0); got[0] != "1" || got[1] != "2" || got[2] != "3" {
+		t.Fatalf("ascending sort = %v", got)
+	}
+
+	m.Sort(0, true)
+	if got := collectColumn(m, 0); got[0] != "3" || got[1] != "2" || got[2] != "1" {
+		t.Fatalf("descending sort = %v", got)
+	}
+}
+
+// TestSortClearsStaleSelection covers the bug where a visual selection
+// (positions into the pre-sort filteredIndex) survived a Sort call, so
+// a subsequent yank/export would grab whatever rows ended up at those
+// positions after reordering instead of the ones the user selected.
+func TestSortClearsStaleSelection(t *testing.T) {
+	m := New(
+		WithColumns([]string{"n"}),
+		WithRows([][]string{{"3"}, {"1"}, {"2"}}),
+	)
+	m.selection = &[2]int{0, 1}
+
+	m.Sort(0, false)
+
+	if m.selection != nil {
+		t.Error("expected selection to be cleared after Sort reorders rows")
+	}
+}
+
+func collectColumn(m Model, col int) []string {
+	values := make([]string, len(m.rows))
+	for i, row := range m.rows {
+		values[i] = row[col]
+	}
+	return values
+}
@@ -0,0 +1,110 @@
+package table
+
+import "fmt"
+
+// RowSource pulls rows from an external reader on demand, so Model can
+// avoid holding an entire dataset in memory upfront. It is the streaming
+// counterpart to WithRows, used for files too large to parse in one go.
+type RowSource interface {
+	// Next returns the next row, or ok=false once the source is
+	// exhausted or a read failed. Callers should check Err after a
+	// false return to tell the two apart.
+	Next() (row []string, ok bool)
+	// Err returns the error that stopped Next, if Next stopped because
+	// of a read error rather than a clean end of input.
+	Err() error
+}
+
+// WithOnDemandRows sets a RowSource that Model pulls rows from lazily, as
+// the viewport scrolls past what's already been loaded, instead of taking
+// a fully materialized rows slice like WithRows does.
+func WithOnDemandRows(source RowSource) Option {
+	return func(m *Model) {
+		m.source = source
+	}
+}
+
+// ensureRowsLoaded pulls rows from the on-demand source, if any, until at
+// least n rows are loaded or the source is exhausted. Newly loaded rows
+// are folded into filteredIndex and paddings incrementally, so the rest of
+// Model doesn't need to know whether rows came from WithRows or a source.
+func (m *Model) ensureRowsLoaded(n int) {
+	if m.source == nil || m.sourceExhausted {
+		return
+	}
+
+	for len(m.rows) < n {
+		row, ok := m.source.Next()
+		if !ok {
+			m.sourceExhausted = true
+			m.sourceErr = m.source.Err()
+			return
+		}
+
+		i := len(m.rows)
+		m.rows = append(m.rows, row)
+		m.extendPaddings(row)
+
+		match, matches := matchRow(m.filterQuery, row)
+		if matches {
+			m.filteredIndex = append(m.filteredIndex, i)
+			if len(match.positions) > 0 {
+				m.cellMatches[i] = match.positions
+			}
+		}
+	}
+}
+
+// ensureVisibleLoaded pulls rows from the source until at least target
+// rows have survived the active filter (i.e. are in filteredIndex), or the
+// source runs out.
+func (m *Model) ensureVisibleLoaded(target int) {
+	if m.source == nil {
+		return
+	}
+	for len(m.filteredIndex) < target && !m.sourceExhausted {
+		m.ensureRowsLoaded(len(m.rows) + 256)
+	}
+}
+
+// drainWarningThreshold is the row count past which drainSource warns that
+// it has pulled the whole file into memory. Sorting and filtering need to
+// see the entire dataset, so there's no way to honor both "stream a window
+// of rows" and "sort/filter across all of them" for a file this large —
+// this only flags it, it doesn't stop the drain.
+const drainWarningThreshold = 250_000
+
+// drainSource pulls every remaining row from the on-demand source. Sorting
+// and filtering need to see the whole dataset, so both fall back to full
+// materialization when a source is in play, reintroducing the memory use
+// that on-demand loading otherwise avoids for files too large to sort or
+// filter this way.
+func (m *Model) drainSource() {
+	if m.source == nil || m.sourceExhausted {
+		return
+	}
+	for !m.sourceExhausted {
+		m.ensureRowsLoaded(len(m.rows) + 4096)
+	}
+	if len(m.rows) > drainWarningThreshold {
+		m.sourceWarning = fmt.Sprintf(
+			"Sorting/filtering loaded all %d rows into memory; large files may use significant memory this way.",
+			len(m.rows))
+	}
+}
+
+func (m *Model) extendPaddings(row []string) {
+	if len(m.paddings) < len(m.cols) {
+		paddings := make([]int, len(m.cols))
+		copy(paddings, m.paddings)
+		m.paddings = paddings
+	}
+	for j, value := range row {
+		if j >= len(m.paddings) {
+			break
+		}
+		if w := len(value) + 1; w > m.paddings[j] {
+			m.paddings[j] = w
+		}
+	}
+}
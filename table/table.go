@@ -1,21 +1,42 @@
 package table
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+var matchStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("212")).
+	Bold(true)
+
 type KeyMap struct {
-	LineUp   key.Binding
-	LineDown key.Binding
-	PageUp   key.Binding
-	PageDown key.Binding
-	ColRight key.Binding
-	ColLeft  key.Binding
+	LineUp      key.Binding
+	LineDown    key.Binding
+	PageUp      key.Binding
+	PageDown    key.Binding
+	ColRight    key.Binding
+	ColLeft     key.Binding
+	Filter      key.Binding
+	Sort        key.Binding
+	SortDesc    key.Binding
+	Preview     key.Binding
+	PreviewUp   key.Binding
+	PreviewDown key.Binding
+	Select      key.Binding
+	Yank        key.Binding
+	Write       key.Binding
+	ColGrow     key.Binding
+	ColShrink   key.Binding
+	Hide        key.Binding
+	UnhideAll   key.Binding
+	Pin         key.Binding
 }
 
 type Model struct {
@@ -30,6 +51,36 @@ type Model struct {
 	viewport viewport.Model
 	start    int
 	end      int
+
+	filterQuery   string
+	filteredIndex []int
+	cellMatches   map[int]map[int][]int
+	filtering     bool
+	filterInput   textinput.Model
+
+	sortState *sortState
+
+	source          RowSource
+	sourceExhausted bool
+	sourceErr       error
+	sourceWarning   string
+
+	previewEnabled  bool
+	previewPosition Position
+	previewViewport viewport.Model
+
+	selecting bool
+	selAnchor int
+	selection *[2]int
+
+	writing     bool
+	writeInput  textinput.Model
+	writeStatus string
+
+	colWidthOverride []int
+	hidden           []bool
+	pinned           []bool
+	maxColWidth      int
 }
 
 func DefaultKeyMap() KeyMap {
@@ -59,6 +110,62 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("left"),
 			key.WithHelp("left", "left"),
 		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort, toggle direction"),
+		),
+		SortDesc: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "sort desc"),
+		),
+		Preview: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "toggle preview"),
+		),
+		PreviewUp: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "scroll preview up"),
+		),
+		PreviewDown: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "scroll preview down"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "visual select"),
+		),
+		Yank: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "yank selection"),
+		),
+		Write: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "write view to file"),
+		),
+		ColGrow: key.NewBinding(
+			key.WithKeys(">"),
+			key.WithHelp(">", "grow column"),
+		),
+		ColShrink: key.NewBinding(
+			key.WithKeys("<"),
+			key.WithHelp("<", "shrink column"),
+		),
+		Hide: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "hide column"),
+		),
+		UnhideAll: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "unhide all columns"),
+		),
+		Pin: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "pin column"),
+		),
 	}
 }
 
@@ -69,16 +176,37 @@ type Option func(*Model)
 
 func New(opts ...Option) Model {
 	m := Model{
-		cursor_row: 0,
-		cursor_col: 0,
-		KeyMap:     DefaultKeyMap(),
+		cursor_row:  0,
+		cursor_col:  0,
+		KeyMap:      DefaultKeyMap(),
+		filterInput: textinput.New(),
+		maxColWidth: defaultMaxColWidth,
 	}
+	m.filterInput.Prompt = "/"
+	m.writeInput = textinput.New()
+	m.writeInput.Prompt = "Write to: "
+	m.previewViewport = newPreviewViewport()
 
 	for _, opt := range opts {
 		opt(&m)
 	}
+
+	m.colWidthOverride = make([]int, len(m.cols))
+	m.hidden = make([]bool, len(m.cols))
+	m.pinned = make([]bool, len(m.cols))
+
+	if m.source != nil {
+		m.paddings = make([]int, len(m.cols))
+		for j, c := range m.cols {
+			m.paddings[j] = len(c) + 1
+		}
+		m.ensureRowsLoaded(30)
+	} else {
+		m.paddings = m.calc_paddings()
+	}
+
 	m.viewport = viewport.New(0, min(30, len(m.rows)))
-	m.paddings = m.calc_paddings()
+	m.applyFilter()
 
 	m.UpdateViewport()
 
@@ -86,7 +214,28 @@ func New(opts ...Option) Model {
 }
 
 func (m Model) View() string {
-	return m.headersView() + "\n" + m.viewport.View()
+	view := m.headersView() + "\n" + m.viewport.View()
+
+	if m.filtering {
+		view += "\n" + m.filterInput.View()
+	}
+	if m.writing {
+		view += "\n" + m.writeInput.View()
+	} else if m.writeStatus != "" {
+		view += "\n" + m.writeStatus
+	}
+	if indicator := m.modeIndicator(); indicator != "" {
+		view += "\n" + indicator
+	}
+	if m.sourceErr != nil {
+		view += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("196")).
+			Render("Error reading more rows: "+m.sourceErr.Error()+" (showing rows loaded so far)")
+	} else if m.sourceWarning != "" {
+		view += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("214")).
+			Render(m.sourceWarning)
+	}
+
+	return m.renderPreview(view)
 }
 
 func WithColumns(cols []string) Option {
@@ -103,6 +252,13 @@ func WithRows(rows [][]string) Option {
 }
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if m.filtering {
+		return m.updateFiltering(msg)
+	}
+	if m.writing {
+		return m.updateWriting(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
@@ -118,6 +274,65 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.MoveLeft(1)
 		case key.Matches(msg, m.KeyMap.ColRight):
 			m.MoveRight(1)
+		case key.Matches(msg, m.KeyMap.Filter):
+			m.filtering = true
+			m.filterInput.SetValue(m.filterQuery)
+			m.filterInput.Focus()
+			return m, textinput.Blink
+		case key.Matches(msg, m.KeyMap.SortDesc):
+			m.Sort(m.cursor_col, true)
+		case key.Matches(msg, m.KeyMap.Sort):
+			m.SortCurrentColumn()
+		case key.Matches(msg, m.KeyMap.Preview):
+			m.previewEnabled = !m.previewEnabled
+			if m.previewEnabled && m.previewPosition == PositionHidden {
+				m.previewPosition = PositionBottom
+			}
+		case key.Matches(msg, m.KeyMap.PreviewUp):
+			m.previewViewport.LineUp(1)
+		case key.Matches(msg, m.KeyMap.PreviewDown):
+			m.previewViewport.LineDown(1)
+		case key.Matches(msg, m.KeyMap.Select):
+			m.selecting = true
+			m.selAnchor = m.cursor_row
+			m.selection = nil
+		case key.Matches(msg, m.KeyMap.Yank):
+			if m.selecting {
+				start, end := m.selAnchor, m.cursor_row
+				if start > end {
+					start, end = end, start
+				}
+				m.selection = &[2]int{start, end}
+				m.selecting = false
+			}
+		case key.Matches(msg, m.KeyMap.Write):
+			m.writing = true
+			m.writeStatus = ""
+			m.writeInput.SetValue("")
+			m.writeInput.Focus()
+			return m, textinput.Blink
+		case key.Matches(msg, m.KeyMap.ColGrow):
+			m.resizeCol(m.cursor_col, 1)
+		case key.Matches(msg, m.KeyMap.ColShrink):
+			m.resizeCol(m.cursor_col, -1)
+		case key.Matches(msg, m.KeyMap.Hide):
+			if m.cursor_col < len(m.hidden) {
+				m.hidden[m.cursor_col] = true
+				m.cursor_col = m.nextVisibleCol(m.cursor_col, 1)
+				if m.isHidden(m.cursor_col) {
+					m.cursor_col = m.nextVisibleCol(m.cursor_col, -1)
+				}
+				m.UpdateViewport()
+			}
+		case key.Matches(msg, m.KeyMap.UnhideAll):
+			for i := range m.hidden {
+				m.hidden[i] = false
+			}
+			m.UpdateViewport()
+		case key.Matches(msg, m.KeyMap.Pin):
+			if m.cursor_col < len(m.pinned) {
+				m.pinned[m.cursor_col] = !m.pinned[m.cursor_col]
+			}
 		}
 
 	}
@@ -125,6 +340,96 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateFiltering handles input while the filter bar is open, applying the
+// query to the table as the user types.
+func (m Model) updateFiltering(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.filtering = false
+			m.filterInput.Blur()
+			m.SetFilter("")
+			return m, nil
+		case "enter":
+			m.filtering = false
+			m.filterInput.Blur()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	m.SetFilter(m.filterInput.Value())
+	return m, cmd
+}
+
+// updateWriting handles input while the "write to file" prompt is open.
+func (m Model) updateWriting(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.writing = false
+			m.writeInput.Blur()
+			return m, nil
+		case "enter":
+			m.writing = false
+			m.writeInput.Blur()
+			filename := m.writeInput.Value()
+			if err := m.Export(filename); err != nil {
+				m.writeStatus = "Error: " + err.Error()
+			} else {
+				m.writeStatus = "Wrote " + filename
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.writeInput, cmd = m.writeInput.Update(msg)
+	return m, cmd
+}
+
+// Export writes the current visible rows (respecting the active filter
+// and sort order), or the yanked selection if one is set, to filename.
+func (m *Model) Export(filename string) error {
+	return ExportRows(filename, m.cols, m.exportableRows())
+}
+
+func (m *Model) exportableRows() [][]string {
+	indices := m.filteredIndex
+	if len(indices) == 0 {
+		return nil
+	}
+
+	if m.selection != nil {
+		start := clamp(m.selection[0], 0, len(indices)-1)
+		end := clamp(m.selection[1], 0, len(indices)-1)
+		indices = indices[start : end+1]
+	}
+
+	rows := make([][]string, len(indices))
+	for i, idx := range indices {
+		rows[i] = m.rows[idx]
+	}
+	return rows
+}
+
+// modeIndicator reports the active visual-selection state, shown below
+// the table like the filter and write prompts.
+func (m Model) modeIndicator() string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
+
+	switch {
+	case m.selecting:
+		return style.Render("-- VISUAL -- (y to yank)")
+	case m.selection != nil:
+		n := m.selection[1] - m.selection[0] + 1
+		return style.Render(fmt.Sprintf("%d row(s) selected (w to write)", n))
+	default:
+		return ""
+	}
+}
+
 func (m Model) headersView() string {
 	s := make([]string, 0, len(m.cols)+1)
 	s = append(s, lipgloss.NewStyle().
@@ -136,9 +441,9 @@ func (m Model) headersView() string {
 		BorderBottom(true).
 		Render(" "))
 
-	for i := m.cursor_col; i < len(m.cols); i++ {
+	for _, i := range m.visibleColumns() {
 		value := m.cols[i]
-		width := m.paddings[i]
+		width := m.colWidthAt(i)
 		textStyle := lipgloss.NewStyle().
 			Inline(true).
 			Bold(true).
@@ -151,20 +456,29 @@ func (m Model) headersView() string {
 			BorderBottom(true).
 			Padding(0, 1)
 
-		s = append(s, cellStyle.Render(textStyle.Render(value)))
+		s = append(s, cellStyle.Render(textStyle.Render(value+m.sortIndicator(i))))
+	}
+
+	header := lipgloss.JoinHorizontal(lipgloss.Top, s...)
+	if m.filterQuery != "" {
+		header += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Render(" (" + strconv.Itoa(len(m.filteredIndex)) + "/" + strconv.Itoa(len(m.rows)) + ")")
 	}
-	return lipgloss.JoinHorizontal(lipgloss.Top, s...)
+	return header
 }
 
 func (m *Model) UpdateViewport() {
-	renderedRows := make([]string, 0, len(m.rows))
+	m.ensureVisibleLoaded(m.cursor_row + m.viewport.Height + 1)
+
+	renderedRows := make([]string, 0, len(m.filteredIndex))
 
 	if m.cursor_row >= 0 {
 		m.start = clamp(m.cursor_row-m.viewport.Height, 0, m.cursor_row)
 	} else {
 		m.start = 0
 	}
-	m.end = clamp(m.cursor_row+m.viewport.Height, m.cursor_row, len(m.rows))
+	m.end = clamp(m.cursor_row+m.viewport.Height, m.cursor_row, len(m.filteredIndex))
 
 	for i := m.start; i < m.end; i++ {
 		renderedRows = append(renderedRows, m.renderRow(i))
@@ -175,24 +489,32 @@ func (m *Model) UpdateViewport() {
 	)
 }
 
+// renderRow renders the row at visible index r (i.e. an index into
+// filteredIndex, not the underlying rows slice).
 func (m *Model) renderRow(r int) string {
+	rowIdx := m.filteredIndex[r]
+	matches := m.cellMatches[rowIdx]
+
 	s := make([]string, 0, len(m.cols)+1)
 	s = append(s, lipgloss.NewStyle().
 		Width(max(len(strconv.Itoa(len(m.rows))), 2)).
 		Align(lipgloss.Right).
 		Align(lipgloss.Right).
-		Render(strconv.FormatInt(int64(r), 10)))
+		Render(strconv.FormatInt(int64(rowIdx), 10)))
 
-	for i := m.cursor_col; i < len(m.rows[r]); i++ {
-		value := m.rows[r][i]
-		width := m.paddings[i]
+	for _, i := range m.visibleColumns() {
+		if i >= len(m.rows[rowIdx]) {
+			continue
+		}
+		value := m.rows[rowIdx][i]
+		width := m.colWidthAt(i)
 		textStyle := lipgloss.NewStyle().
 			Width(width).
 			MaxWidth(width).
 			Inline(true)
 		cellStyle := lipgloss.NewStyle().
 			Padding(0, 1)
-		s = append(s, cellStyle.Render(textStyle.Render(value)))
+		s = append(s, cellStyle.Render(textStyle.Render(highlightCell(value, matches[i]))))
 	}
 
 	row := lipgloss.JoinHorizontal(lipgloss.Top, s...)
@@ -207,6 +529,30 @@ func (m *Model) renderRow(r int) string {
 	return row
 }
 
+// highlightCell re-renders value with the runes at the given positions
+// (as produced by fuzzyScore) styled via matchStyle.
+func highlightCell(value string, positions []int) string {
+	if len(positions) == 0 {
+		return value
+	}
+
+	runes := []rune(value)
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+
+	var b strings.Builder
+	for i, r := range runes {
+		if matched[i] {
+			b.WriteString(matchStyle.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 func (m *Model) calc_paddings() []int {
 	var elements [][]string
 	elements = append(elements, m.cols)
@@ -227,7 +573,8 @@ func (m *Model) calc_paddings() []int {
 // MoveUp moves the selection up by any number of rows.
 // It can not go above the first row.
 func (m *Model) MoveUp(n int) {
-	m.cursor_row = clamp(m.cursor_row-n, 0, len(m.rows)-1)
+	m.resetPreviewScroll()
+	m.cursor_row = clamp(m.cursor_row-n, 0, len(m.filteredIndex)-1)
 	switch {
 	case m.start == 0:
 		m.viewport.SetYOffset(clamp(m.viewport.YOffset, 0, m.cursor_row))
@@ -242,11 +589,13 @@ func (m *Model) MoveUp(n int) {
 // MoveDown moves the selection down by any number of rows.
 // It can not go below the last row.
 func (m *Model) MoveDown(n int) {
-	m.cursor_row = clamp(m.cursor_row+n, 0, len(m.rows)-1)
+	m.resetPreviewScroll()
+	m.ensureVisibleLoaded(m.cursor_row + n + 1)
+	m.cursor_row = clamp(m.cursor_row+n, 0, len(m.filteredIndex)-1)
 	m.UpdateViewport()
 
 	switch {
-	case m.end == len(m.rows) && m.viewport.YOffset > 0:
+	case m.end == len(m.filteredIndex) && m.viewport.YOffset > 0:
 		m.viewport.SetYOffset(clamp(m.viewport.YOffset-n, 1, m.viewport.Height))
 	case m.cursor_row > (m.end-m.start)/2 && m.viewport.YOffset > 0:
 		m.viewport.SetYOffset(clamp(m.viewport.YOffset-n, 1, m.cursor_row))
@@ -257,12 +606,18 @@ func (m *Model) MoveDown(n int) {
 }
 
 func (m *Model) MoveRight(n int) {
-	m.cursor_col = clamp(m.cursor_col+n, 0, len(m.cols)-1)
+	m.resetPreviewScroll()
+	for i := 0; i < n; i++ {
+		m.cursor_col = m.nextVisibleCol(m.cursor_col, 1)
+	}
 	m.UpdateViewport()
 }
 
 func (m *Model) MoveLeft(n int) {
-	m.cursor_col = clamp(m.cursor_col-n, 0, len(m.cols)-1)
+	m.resetPreviewScroll()
+	for i := 0; i < n; i++ {
+		m.cursor_col = m.nextVisibleCol(m.cursor_col, -1)
+	}
 	m.UpdateViewport()
 }
 